@@ -0,0 +1,706 @@
+// Package ttlcache implements an in-memory cache with per-item and global
+// time-to-live expiration.
+package ttlcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EvictionReason describes why an item left the cache, as reported through
+// SetExpirationReasonCallback.
+type EvictionReason int
+
+const (
+	// Expired means the item's TTL ran out.
+	Expired EvictionReason = iota + 1
+	// EvictedSize means the item was evicted to keep the cache within the
+	// limit set by SetCacheSizeLimit.
+	EvictedSize
+	// Removed means the item was removed via Remove or Purge.
+	Removed
+	// Closed means the item was still present when the cache was Close'd.
+	Closed
+	// NegativeCacheExpired means a cached loader error, stored via
+	// SetNegativeCacheTTL, reached the end of its negative-cache TTL. It is
+	// only counted by the eviction counter of RegisterPrometheus, when a Get
+	// finds the entry expired; it is never passed to
+	// SetExpirationReasonCallback.
+	NegativeCacheExpired
+)
+
+// ExpirationCallback is invoked whenever an item leaves the cache, for any
+// reason. See SetExpirationCallback.
+type ExpirationCallback[K comparable, V any] func(key K, value V)
+
+// CheckExpirationCallback is consulted when an item's TTL has run out and
+// may veto the expiration by returning false, in which case the item's TTL
+// is reset and it is checked again later. See SetCheckExpirationCallback.
+type CheckExpirationCallback[K comparable, V any] func(key K, value V) bool
+
+// NewItemCallback is invoked whenever a key is added to the cache for the
+// first time. See SetNewItemCallback.
+type NewItemCallback[K comparable, V any] func(key K, value V)
+
+// ExpirationReasonCallback is like ExpirationCallback but also receives the
+// EvictionReason. See SetExpirationReasonCallback.
+type ExpirationReasonCallback[K comparable, V any] func(key K, reason EvictionReason, value V)
+
+// LoaderFunction fetches data for a key that is missing from the cache. See
+// SetLoaderFunction.
+type LoaderFunction[K comparable, V any] func(key K) (data V, ttl time.Duration, err error)
+
+// loaderCall tracks a single in-flight loader invocation so that concurrent
+// Get misses for the same key share its result instead of each calling the
+// loader themselves. ctx is cancelled once every waiter's own context (see
+// join) has finished, so a loader that respects ctx doesn't keep working
+// once nobody is left to receive its result.
+type loaderCall[V any] struct {
+	done    chan struct{}
+	data    V
+	err     error
+	ctx     context.Context
+	cancel  context.CancelFunc
+	waiters int32
+}
+
+// newLoaderCall derives the call's context from ctx, the initiating
+// caller's context, so the loader sees its values. ctx's deadline and
+// cancellation are not inherited: the call's context is cancelled only
+// through join, once every waiter's context has finished.
+func newLoaderCall[V any](ctx context.Context) *loaderCall[V] {
+	ctx, cancel := context.WithCancel(valuesOnlyContext{ctx})
+	return &loaderCall[V]{done: make(chan struct{}), ctx: ctx, cancel: cancel}
+}
+
+// valuesOnlyContext exposes the values of the wrapped context but never
+// reports a deadline or cancellation.
+type valuesOnlyContext struct{ context.Context }
+
+func (valuesOnlyContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (valuesOnlyContext) Done() <-chan struct{}       { return nil }
+func (valuesOnlyContext) Err() error                  { return nil }
+
+// Cache is a synchronized map of K keys to V values where each entry
+// expires after a TTL, either global or set on a per-item basis. The zero
+// value is not usable; create one with NewCacheOf (or NewCache for the
+// string-keyed, interface{}-valued instantiation kept for compatibility
+// with earlier, non-generic versions of this package).
+type Cache[K comparable, V any] struct {
+	mutex sync.Mutex
+	ttl   time.Duration
+	items map[K]*item[K, V]
+	wheel *timingWheel[K, V]
+
+	expirationCallback       ExpirationCallback[K, V]
+	checkExpirationCallback  CheckExpirationCallback[K, V]
+	newItemCallback          NewItemCallback[K, V]
+	expirationReasonCallback ExpirationReasonCallback[K, V]
+
+	loaderFunction   ContextLoaderFunction[K, V]
+	loaderLock       sync.Mutex
+	loaderCalls      map[K]*loaderCall[V]
+	negativeCacheTTL time.Duration
+	negativeCache    map[K]negativeEntry
+
+	skipTTLExtensionOnHit bool
+	sizeLimit             int
+	policy                Policy[K]
+
+	metrics   Metrics
+	collector *promCollector
+	tracer    trace.Tracer
+
+	store          Store[K, V]
+	writeMode      WriteMode
+	readThrough    bool
+	writeBackQueue chan writeBackEntry[K, V]
+
+	isClosed       bool
+	shutdownSignal chan struct{}
+}
+
+// NewCacheOf creates a new Cache[K, V] with no expiration limit and starts
+// its background expiration processing goroutine. Call Close when done
+// with it.
+func NewCacheOf[K comparable, V any]() *Cache[K, V] {
+	c := &Cache[K, V]{
+		items:          make(map[K]*item[K, V]),
+		wheel:          newTimingWheel[K, V](),
+		loaderCalls:    make(map[K]*loaderCall[V]),
+		negativeCache:  make(map[K]negativeEntry),
+		shutdownSignal: make(chan struct{}),
+		policy:         NewFIFOPolicy[K](),
+	}
+	go c.startExpirationProcessing()
+	return c
+}
+
+// NewCache creates a new string-keyed, interface{}-valued Cache. It is kept
+// for backward compatibility with earlier, non-generic versions of this
+// package; new code should prefer NewCacheOf with concrete types.
+func NewCache() *Cache[string, interface{}] {
+	return NewCacheOf[string, interface{}]()
+}
+
+// SetTTL sets the global TTL used by items added via Set (as opposed to
+// SetWithTTL). It takes effect immediately for items already tracking the
+// global TTL.
+func (c *Cache[K, V]) SetTTL(ttl time.Duration) error {
+	c.mutex.Lock()
+	if c.isClosed {
+		c.mutex.Unlock()
+		return ErrClosed
+	}
+	c.ttl = ttl
+	now := time.Now()
+	for _, it := range c.items {
+		if it.ttl == ItemExpireWithGlobalTTL {
+			it.touch(ttl)
+			c.wheel.reschedule(it, now)
+		}
+	}
+	c.mutex.Unlock()
+	return nil
+}
+
+// SetExpirationCallback sets the callback invoked whenever an item leaves
+// the cache, regardless of the reason.
+func (c *Cache[K, V]) SetExpirationCallback(callback ExpirationCallback[K, V]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.expirationCallback = callback
+}
+
+// SetExpirationReasonCallback is like SetExpirationCallback but also reports
+// the EvictionReason.
+func (c *Cache[K, V]) SetExpirationReasonCallback(callback ExpirationReasonCallback[K, V]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.expirationReasonCallback = callback
+}
+
+// SetCheckExpirationCallback sets a callback consulted before an item is
+// expired. Returning false keeps the item in the cache and defers its
+// expiration.
+func (c *Cache[K, V]) SetCheckExpirationCallback(callback CheckExpirationCallback[K, V]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.checkExpirationCallback = callback
+}
+
+// SetNewItemCallback sets the callback invoked the first time a key is
+// added to the cache.
+func (c *Cache[K, V]) SetNewItemCallback(callback NewItemCallback[K, V]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.newItemCallback = callback
+}
+
+// SetLoaderFunction sets the function used to populate the cache on a miss
+// in Get. Concurrent misses for the same key share a single loader call.
+// See SetContextLoaderFunction for a variant that receives a context.
+func (c *Cache[K, V]) SetLoaderFunction(loader LoaderFunction[K, V]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if loader == nil {
+		c.loaderFunction = nil
+		return
+	}
+	c.loaderFunction = func(ctx context.Context, key K) (V, time.Duration, error) {
+		return loader(key)
+	}
+}
+
+// SkipTTLExtensionOnHit controls whether Get resets an item's TTL. By
+// default a hit extends the TTL; pass true to make items expire strictly by
+// their original deadline.
+func (c *Cache[K, V]) SkipTTLExtensionOnHit(skip bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.skipTTLExtensionOnHit = skip
+}
+
+// SetCacheSizeLimit caps the number of items the cache holds. Once the limit
+// is reached, adding a new item evicts whichever key the cache's eviction
+// Policy picks as the victim (FIFO-by-insertion-order by default; see
+// SetEvictionPolicy). A limit of 0 (the default) disables the cap.
+func (c *Cache[K, V]) SetCacheSizeLimit(limit int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.sizeLimit = limit
+	c.evictOversizeItems()
+}
+
+// SetEvictionPolicy replaces the Policy consulted by SetCacheSizeLimit to
+// pick an eviction victim. The policy is seeded with the cache's current
+// keys via OnAdd, so any frequency/recency bookkeeping starts fresh. A nil
+// policy resets the cache to its default FIFO policy.
+func (c *Cache[K, V]) SetEvictionPolicy(policy Policy[K]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if policy == nil {
+		policy = NewFIFOPolicy[K]()
+	}
+	c.policy = policy
+	for key := range c.items {
+		c.policy.OnAdd(key)
+	}
+}
+
+// Set adds data to the cache under key, using the cache's global TTL.
+func (c *Cache[K, V]) Set(key K, data V) error {
+	return c.SetWithTTL(key, data, ItemExpireWithGlobalTTL)
+}
+
+// SetWithTTL adds data to the cache under key with its own TTL, independent
+// of the cache's global TTL.
+func (c *Cache[K, V]) SetWithTTL(key K, data V, ttl time.Duration) error {
+	c.mutex.Lock()
+	if c.isClosed {
+		c.mutex.Unlock()
+		return ErrClosed
+	}
+	c.setWithTTL(key, data, ttl)
+
+	effectiveTTL := ttl
+	if effectiveTTL == ItemExpireWithGlobalTTL {
+		effectiveTTL = c.ttl
+	}
+	var expireAt time.Time
+	if it, found := c.items[key]; found && effectiveTTL > 0 {
+		expireAt = it.expireAt
+	}
+	store := c.store
+	writeMode := c.writeMode
+	writeBackQueue := c.writeBackQueue
+	c.mutex.Unlock()
+
+	if store != nil {
+		if writeMode == WriteBack {
+			queueWriteBack(writeBackQueue, writeBackEntry[K, V]{store, key, data, expireAt})
+		} else if err := store.Set(key, data, expireAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setWithTTL does the actual insertion work. c.mutex must be held.
+func (c *Cache[K, V]) setWithTTL(key K, data V, ttl time.Duration) {
+	now := time.Now()
+	if it, found := c.items[key]; found {
+		it.data = data
+		it.ttl = ttl
+		it.touch(c.ttl)
+		c.wheel.reschedule(it, now)
+		c.policy.OnAccess(key)
+	} else {
+		it := newItem(key, data, ttl, c.ttl)
+		c.items[key] = it
+		c.wheel.schedule(it, now)
+		c.policy.OnAdd(key)
+		if c.newItemCallback != nil {
+			c.newItemCallback(key, data)
+		}
+	}
+	c.metrics.Inserted++
+	if c.collector != nil {
+		c.collector.inserted.Inc()
+	}
+	c.evictOversizeItems()
+}
+
+// evictOversizeItems evicts the victims picked by the cache's eviction
+// Policy until the cache is back within sizeLimit. c.mutex must be held.
+func (c *Cache[K, V]) evictOversizeItems() {
+	if c.sizeLimit <= 0 {
+		return
+	}
+	for len(c.items) > c.sizeLimit {
+		key, ok := c.policy.Victim()
+		if !ok {
+			return
+		}
+		it, found := c.items[key]
+		if !found {
+			// The policy's bookkeeping is stale (e.g. a key it never saw
+			// OnRemove for); drop it and ask again.
+			c.policy.OnRemove(key)
+			continue
+		}
+		c.removeItem(it, EvictedSize)
+	}
+}
+
+// Get returns the value stored under key. If key is missing and a loader
+// function is set, it is invoked (once, even under concurrent callers) to
+// populate the cache. It is equivalent to GetWithContext with
+// context.Background() and no options.
+func (c *Cache[K, V]) Get(key K) (V, error) {
+	return c.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext is like Get, but ctx is passed to the loader function set
+// via SetContextLoaderFunction (ignored by a plain LoaderFunction set via
+// SetLoaderFunction), and is cancelled once every concurrent caller sharing
+// the same key's in-flight load has had its own context finish. opts can
+// carry WithSkipNegativeCache to opt this call out of negative caching.
+func (c *Cache[K, V]) GetWithContext(ctx context.Context, key K, opts ...LoaderOption) (V, error) {
+	c.mutex.Lock()
+	if c.isClosed {
+		c.mutex.Unlock()
+		var zero V
+		return zero, ErrClosed
+	}
+
+	end := startSpan(c.tracer, "Cache.Get", key)
+	defer end()
+
+	if it, found := c.items[key]; found {
+		c.metrics.Retrievals++
+		if c.collector != nil {
+			c.collector.hits.Inc()
+		}
+		c.policy.OnAccess(key)
+		if !c.skipTTLExtensionOnHit {
+			it.touch(c.ttl)
+			c.wheel.reschedule(it, time.Now())
+		}
+		data := it.data
+		c.mutex.Unlock()
+		return data, nil
+	}
+
+	var options loaderOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if entry, found := c.negativeCache[key]; found && !options.skipNegativeCache {
+		if time.Now().Before(entry.expiresAt) {
+			c.metrics.NegativeCacheHits++
+			if c.collector != nil {
+				c.collector.negativeCacheHits.Inc()
+			}
+			c.mutex.Unlock()
+			var zero V
+			return zero, entry.err
+		}
+		delete(c.negativeCache, key)
+		if c.collector != nil {
+			c.collector.observeEviction(NegativeCacheExpired, 0)
+		}
+	}
+
+	c.metrics.Misses++
+	if c.collector != nil {
+		c.collector.misses.Inc()
+	}
+	loader := c.loaderFunction
+	store := c.store
+	readThrough := c.readThrough
+	tracer := c.tracer
+	collector := c.collector
+	c.mutex.Unlock()
+
+	if readThrough && store != nil {
+		if data, found := c.getFromStore(store, key); found {
+			return data, nil
+		}
+	}
+
+	if loader == nil {
+		var zero V
+		return zero, ErrNotFound
+	}
+
+	return c.load(ctx, key, loader, tracer, collector, options)
+}
+
+// getFromStore consults store for key on a ReadThrough miss, populating
+// the cache when found.
+func (c *Cache[K, V]) getFromStore(store Store[K, V], key K) (V, bool) {
+	data, expiresAt, err := store.Get(key)
+	if err != nil {
+		var zero V
+		return zero, false
+	}
+
+	ttl := ItemNotExpire
+	if !expiresAt.IsZero() {
+		if ttl = time.Until(expiresAt); ttl <= 0 {
+			var zero V
+			return zero, false
+		}
+	}
+
+	c.mutex.Lock()
+	if !c.isClosed {
+		c.setWithTTL(key, data, ttl)
+	}
+	c.mutex.Unlock()
+	return data, true
+}
+
+// load runs loader for key, collapsing concurrent callers into a single
+// call via a per-key loaderCall. Every caller joins the call with its own
+// ctx; the loader's own context is cancelled once every joined ctx has
+// finished, so a loader that respects it can abort once nobody is left to
+// receive its result.
+func (c *Cache[K, V]) load(ctx context.Context, key K, loader ContextLoaderFunction[K, V], tracer trace.Tracer, collector *promCollector, opts loaderOptions) (V, error) {
+	c.loaderLock.Lock()
+	if call, inflight := c.loaderCalls[key]; inflight {
+		call.join(ctx)
+		c.loaderLock.Unlock()
+		<-call.done
+		return call.data, call.err
+	}
+
+	call := newLoaderCall[V](ctx)
+	call.join(ctx)
+	c.loaderCalls[key] = call
+	c.loaderLock.Unlock()
+
+	end := startSpan(tracer, "Cache.Load", key)
+	start := time.Now()
+	data, ttl, err := loader(call.ctx, key)
+	if collector != nil {
+		collector.loaderLatency.Observe(time.Since(start).Seconds())
+	}
+	end()
+	call.data, call.err = data, err
+
+	c.loaderLock.Lock()
+	delete(c.loaderCalls, key)
+	c.loaderLock.Unlock()
+	close(call.done)
+
+	c.mutex.Lock()
+	if c.isClosed {
+		c.mutex.Unlock()
+		var zero V
+		return zero, ErrClosed
+	}
+	if err == nil {
+		c.setWithTTL(key, data, ttl)
+	} else if c.negativeCacheTTL > 0 && !opts.skipNegativeCache {
+		c.negativeCache[key] = negativeEntry{err: err, expiresAt: time.Now().Add(c.negativeCacheTTL)}
+	}
+	c.mutex.Unlock()
+
+	return data, err
+}
+
+// Remove deletes key from the cache, firing the expiration callbacks with
+// reason Removed. It returns ErrNotFound if key is not present.
+func (c *Cache[K, V]) Remove(key K) error {
+	c.mutex.Lock()
+	if c.isClosed {
+		c.mutex.Unlock()
+		return ErrClosed
+	}
+	it, found := c.items[key]
+	if !found {
+		c.mutex.Unlock()
+		return ErrNotFound
+	}
+	c.removeItem(it, Removed)
+	store := c.store
+	c.mutex.Unlock()
+
+	if store != nil {
+		return store.Delete(key)
+	}
+	return nil
+}
+
+// Purge empties the cache.
+func (c *Cache[K, V]) Purge() error {
+	c.mutex.Lock()
+	if c.isClosed {
+		c.mutex.Unlock()
+		return ErrClosed
+	}
+	items := c.items
+	c.items = make(map[K]*item[K, V])
+	c.negativeCache = make(map[K]negativeEntry)
+	c.metrics.Evicted += int64(len(items))
+	for key, it := range items {
+		c.wheel.remove(it)
+		c.policy.OnRemove(key)
+		if c.collector != nil {
+			c.collector.observeEviction(Removed, time.Until(it.expireAt))
+		}
+		if c.tracer != nil {
+			startSpan(c.tracer, "Cache.Evict", key, attribute.String("reason", reasonLabel(Removed)))()
+		}
+	}
+	cb := c.expirationCallback
+	rcb := c.expirationReasonCallback
+	c.mutex.Unlock()
+
+	if cb != nil || rcb != nil {
+		go func() {
+			for _, it := range items {
+				if cb != nil {
+					cb(it.key, it.data)
+				}
+				if rcb != nil {
+					rcb(it.key, Removed, it.data)
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+// Count returns the number of items currently in the cache.
+func (c *Cache[K, V]) Count() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.items)
+}
+
+// GetMetrics returns a snapshot of the cache's activity counters.
+func (c *Cache[K, V]) GetMetrics() Metrics {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	m := c.metrics
+	m.Hits = int64(len(c.items))
+	return m
+}
+
+// Close stops the cache's background expiration processing and evicts all
+// remaining items with reason Closed. Further calls return ErrClosed.
+func (c *Cache[K, V]) Close() error {
+	c.mutex.Lock()
+	if c.isClosed {
+		c.mutex.Unlock()
+		return ErrClosed
+	}
+	c.isClosed = true
+	close(c.shutdownSignal)
+	if c.writeBackQueue != nil {
+		close(c.writeBackQueue)
+	}
+
+	items := make([]*item[K, V], 0, len(c.items))
+	for _, it := range c.items {
+		items = append(items, it)
+	}
+	c.items = make(map[K]*item[K, V])
+	c.negativeCache = make(map[K]negativeEntry)
+	c.metrics.Evicted += int64(len(items))
+	for _, it := range items {
+		c.wheel.remove(it)
+		c.policy.OnRemove(it.key)
+		if c.collector != nil {
+			c.collector.observeEviction(Closed, time.Until(it.expireAt))
+		}
+		if c.tracer != nil {
+			startSpan(c.tracer, "Cache.Evict", it.key, attribute.String("reason", reasonLabel(Closed)))()
+		}
+	}
+	cb := c.expirationCallback
+	rcb := c.expirationReasonCallback
+	c.mutex.Unlock()
+
+	if cb != nil || rcb != nil {
+		go func() {
+			for _, it := range items {
+				if cb != nil {
+					cb(it.key, it.data)
+				}
+				if rcb != nil {
+					rcb(it.key, Closed, it.data)
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+// removeItem evicts it from the cache and fires the expiration callbacks
+// asynchronously, so that callers invoking Remove/Set from within a
+// callback cannot deadlock against themselves. c.mutex must be held.
+func (c *Cache[K, V]) removeItem(it *item[K, V], reason EvictionReason) {
+	c.wheel.remove(it)
+	delete(c.items, it.key)
+	c.policy.OnRemove(it.key)
+	c.metrics.Evicted++
+	if c.collector != nil {
+		c.collector.observeEviction(reason, time.Until(it.expireAt))
+	}
+	if c.tracer != nil {
+		startSpan(c.tracer, "Cache.Evict", it.key, attribute.String("reason", reasonLabel(reason)))()
+	}
+
+	cb := c.expirationCallback
+	rcb := c.expirationReasonCallback
+	if cb == nil && rcb == nil {
+		return
+	}
+	key, data := it.key, it.data
+	go func() {
+		if cb != nil {
+			cb(key, data)
+		}
+		if rcb != nil {
+			rcb(key, reason, data)
+		}
+	}()
+}
+
+// startExpirationProcessing ticks the hierarchical timing wheel at its
+// finest resolution, firing (or, if checkExpirationCallback vetoes it,
+// re-touching and rescheduling) whichever items its current tick reaches.
+// Set/Get only ever need to reschedule the single item they touch, rather
+// than repositioning a priority queue or resetting a timer against its new
+// head.
+//
+// time.Ticker coalesces ticks it can't deliver promptly into a single one,
+// so under load the wheel's logical clock can fall behind wall-clock time.
+// Catch up by advancing once per wheelTick actually elapsed since the last
+// delivered tick, rather than once per delivery.
+func (c *Cache[K, V]) startExpirationProcessing() {
+	ticker := time.NewTicker(wheelTick)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case now := <-ticker.C:
+			ticks := int(now.Sub(last) / wheelTick)
+			if ticks < 1 {
+				ticks = 1
+			}
+			last = last.Add(time.Duration(ticks) * wheelTick)
+
+			c.mutex.Lock()
+			for i := 0; i < ticks; i++ {
+				c.wheel.advance(now, c.expireItem)
+			}
+			c.mutex.Unlock()
+		case <-c.shutdownSignal:
+			return
+		}
+	}
+}
+
+// expireItem is the timingWheel's fire callback for an item its tick
+// reached: it consults checkExpirationCallback, re-touching and
+// rescheduling the item if it vetoes expiration, and otherwise removes it
+// with reason Expired. c.mutex must be held.
+func (c *Cache[K, V]) expireItem(it *item[K, V]) {
+	if c.checkExpirationCallback != nil && !c.checkExpirationCallback(it.key, it.data) {
+		it.touch(c.ttl)
+		c.wheel.schedule(it, time.Now())
+		return
+	}
+	c.removeItem(it, Expired)
+}
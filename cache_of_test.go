@@ -0,0 +1,59 @@
+package ttlcache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/witchu/ttlcache/v2"
+)
+
+// NewCacheOf returns values of the instantiated type, so callers don't need
+// the interface{} type assertions NewCache requires.
+func TestCacheOf_TypedGetSet(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCacheOf[string, int]()
+	defer cache.Close()
+
+	cache.SetTTL(time.Hour)
+	cache.Set("answer", 42)
+
+	value, err := cache.Get("answer")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 42, value)
+
+	_, err = cache.Get("missing")
+	assert.Equal(t, ErrNotFound, err)
+	assert.Equal(t, 0, func() int { v, _ := cache.Get("missing"); return v }())
+}
+
+// Callbacks on a typed cache receive and return the instantiated types
+// directly.
+func TestCacheOf_TypedCallbacksAndLoader(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCacheOf[int, string]()
+	defer cache.Close()
+
+	var expiredKey int
+	var expiredValue string
+	sync := make(chan struct{}, 1)
+	cache.SetExpirationCallback(func(key int, value string) {
+		expiredKey, expiredValue = key, value
+		sync <- struct{}{}
+	})
+
+	cache.SetLoaderFunction(func(key int) (string, time.Duration, error) {
+		return "loaded", 0, nil
+	})
+
+	value, err := cache.Get(7)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "loaded", value)
+
+	assert.Equal(t, nil, cache.Remove(7))
+	<-sync
+	assert.Equal(t, 7, expiredKey)
+	assert.Equal(t, "loaded", expiredValue)
+}
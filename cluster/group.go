@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/witchu/ttlcache/v2"
+)
+
+// Group is a logical cache shared by a set of peer processes: Get routes a
+// key to whichever peer owns it via PeerPicker, fetching through the local
+// ttlcache.Cache (and its loader function) when this process is the owner,
+// or over Transport when it isn't. Values fetched from a peer are kept in
+// a bounded-TTL local "hot cache" so repeat reads of the same key don't
+// cross the network every time.
+type Group struct {
+	name      string
+	picker    PeerPicker
+	transport Transport
+	local     *ttlcache.Cache[string, []byte]
+	hot       *ttlcache.Cache[string, []byte]
+}
+
+// NewGroup creates a Group named name. Add it to an HTTPPool to make it
+// reachable by other peers under that name. hotTTL bounds how long a value fetched
+// from a peer is kept in the local hot cache. loader supplies the
+// authoritative value for a key this process owns; it runs through the
+// local cache's own singleflight, so concurrent misses for the same key
+// only call it once.
+func NewGroup(name string, hotTTL time.Duration, picker PeerPicker, transport Transport, loader ttlcache.LoaderFunction[string, []byte]) *Group {
+	local := ttlcache.NewCacheOf[string, []byte]()
+	local.SetLoaderFunction(loader)
+
+	hot := ttlcache.NewCacheOf[string, []byte]()
+	hot.SetTTL(hotTTL)
+
+	return &Group{
+		name:      name,
+		picker:    picker,
+		transport: transport,
+		local:     local,
+		hot:       hot,
+	}
+}
+
+// Name returns the group's name, as served under /_ttlcache/<name>/... by
+// HTTPPool.
+func (g *Group) Name() string {
+	return g.name
+}
+
+// Close shuts down the Group's local and hot caches.
+func (g *Group) Close() error {
+	g.hot.Close()
+	return g.local.Close()
+}
+
+// Get returns the value for key, routing to its owning peer and
+// populating the local hot cache on a remote fetch.
+func (g *Group) Get(ctx context.Context, key string) ([]byte, error) {
+	peer, isSelf := g.picker.PickPeer(key)
+	if isSelf {
+		return g.getLocal(key)
+	}
+
+	if data, err := g.hot.Get(key); err == nil {
+		return data, nil
+	}
+
+	data, err := g.transport.Fetch(ctx, peer, g.name, key)
+	if err != nil {
+		return nil, fmt.Errorf("cluster: fetching %q from %s: %w", key, peer, err)
+	}
+	_ = g.hot.Set(key, data)
+	return data, nil
+}
+
+// getLocal answers a key this process owns. It is also what HTTPPool calls
+// to answer other peers' Fetch requests.
+func (g *Group) getLocal(key string) ([]byte, error) {
+	return g.local.Get(key)
+}
@@ -0,0 +1,82 @@
+// Package cluster lets multiple ttlcache processes share a logical cache:
+// Group routes a Get for a key to whichever peer owns it via consistent
+// hashing, running the loader only on the owning peer and keeping a
+// bounded-TTL "hot cache" of values fetched from other peers, following the
+// groupcache model.
+package cluster
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// PeerPicker maps a key to the peer that owns it.
+type PeerPicker interface {
+	// PickPeer returns the address of the peer that owns key, and whether
+	// that peer is this process itself.
+	PickPeer(key string) (peer string, isSelf bool)
+}
+
+// HashRing is a PeerPicker based on consistent hashing: each peer address
+// is hashed onto replicas points on a ring, so adding or removing a peer
+// only reshuffles ownership of the keys nearest to it instead of the whole
+// keyspace.
+type HashRing struct {
+	mu       sync.RWMutex
+	self     string
+	replicas int
+	ring     []uint32
+	nodes    map[uint32]string
+}
+
+// NewHashRing creates a HashRing that identifies self (this process's own
+// peer address, used to answer PickPeer's isSelf) and distributes each
+// peer over replicas points on the ring. replicas <= 0 defaults to 160,
+// matching groupcache's default.
+func NewHashRing(self string, replicas int) *HashRing {
+	if replicas <= 0 {
+		replicas = 160
+	}
+	return &HashRing{
+		self:     self,
+		replicas: replicas,
+		nodes:    make(map[uint32]string),
+	}
+}
+
+// RegisterPeers replaces the full set of peers the ring knows about,
+// including self if it also serves requests for other peers.
+func (h *HashRing) RegisterPeers(peers []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.ring = h.ring[:0]
+	h.nodes = make(map[uint32]string, len(peers)*h.replicas)
+	for _, peer := range peers {
+		for r := 0; r < h.replicas; r++ {
+			hash := crc32.ChecksumIEEE([]byte(strconv.Itoa(r) + peer))
+			h.ring = append(h.ring, hash)
+			h.nodes[hash] = peer
+		}
+	}
+	sort.Slice(h.ring, func(i, j int) bool { return h.ring[i] < h.ring[j] })
+}
+
+// PickPeer implements PeerPicker.
+func (h *HashRing) PickPeer(key string) (string, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.ring) == 0 {
+		return h.self, true
+	}
+	hash := crc32.ChecksumIEEE([]byte(key))
+	idx := sort.Search(len(h.ring), func(i int) bool { return h.ring[i] >= hash })
+	if idx == len(h.ring) {
+		idx = 0
+	}
+	peer := h.nodes[h.ring[idx]]
+	return peer, peer == h.self
+}
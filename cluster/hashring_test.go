@@ -0,0 +1,35 @@
+package cluster_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/witchu/ttlcache/v2/cluster"
+)
+
+// PickPeer is stable for a given ring: the same key always maps to the
+// same peer until the peer set changes.
+func TestHashRing_PickPeerIsStable(t *testing.T) {
+	t.Parallel()
+
+	ring := cluster.NewHashRing("peer-a", 0)
+	ring.RegisterPeers([]string{"peer-a", "peer-b", "peer-c"})
+
+	peer, isSelf := ring.PickPeer("some-key")
+	assert.NotEmpty(t, peer)
+
+	again, isSelfAgain := ring.PickPeer("some-key")
+	assert.Equal(t, peer, again)
+	assert.Equal(t, isSelf, isSelfAgain)
+}
+
+// An empty ring routes every key to self.
+func TestHashRing_EmptyRingPicksSelf(t *testing.T) {
+	t.Parallel()
+
+	ring := cluster.NewHashRing("peer-a", 0)
+
+	peer, isSelf := ring.PickPeer("some-key")
+	assert.Equal(t, "peer-a", peer)
+	assert.True(t, isSelf)
+}
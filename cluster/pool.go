@@ -0,0 +1,52 @@
+package cluster
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// HTTPPool serves a Group's locally-owned keys to other peers over HTTP,
+// the counterpart to HTTPTransport. Mount it at the root of an HTTP server
+// reachable at the address peers were registered with.
+type HTTPPool struct {
+	groups map[string]*Group
+}
+
+// NewHTTPPool creates an empty HTTPPool; add groups to it with Add.
+func NewHTTPPool() *HTTPPool {
+	return &HTTPPool{groups: make(map[string]*Group)}
+}
+
+// Add makes group reachable under its Name at /_ttlcache/<name>/<key>.
+func (p *HTTPPool) Add(group *Group) {
+	p.groups[group.Name()] = group
+}
+
+// ServeHTTP implements http.Handler.
+func (p *HTTPPool) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/_ttlcache/"), "/", 2)
+	if len(parts) != 2 {
+		http.Error(w, "cluster: malformed request", http.StatusBadRequest)
+		return
+	}
+
+	group, found := p.groups[parts[0]]
+	if !found {
+		http.Error(w, "cluster: unknown group", http.StatusNotFound)
+		return
+	}
+
+	key, err := url.PathUnescape(parts[1])
+	if err != nil {
+		http.Error(w, "cluster: malformed key", http.StatusBadRequest)
+		return
+	}
+
+	data, err := group.getLocal(key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,55 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Transport fetches key, belonging to group, from the peer at addr. It is
+// the pluggable counterpart to HTTPPool, which serves requests built the
+// same way on the peer side; tests and non-HTTP deployments can supply
+// their own implementation instead of HTTPTransport.
+type Transport interface {
+	Fetch(ctx context.Context, addr, group, key string) ([]byte, error)
+}
+
+// HTTPTransport fetches keys from peers over plain HTTP, hitting the path
+// an HTTPPool serves on each peer.
+type HTTPTransport struct {
+	Client *http.Client
+}
+
+// NewHTTPTransport returns an HTTPTransport using http.DefaultClient.
+func NewHTTPTransport() *HTTPTransport {
+	return &HTTPTransport{}
+}
+
+// Fetch implements Transport.
+func (t *HTTPTransport) Fetch(ctx context.Context, addr, group, key string) ([]byte, error) {
+	u := fmt.Sprintf("%s/_ttlcache/%s/%s", addr, url.PathEscape(group), url.PathEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cluster: peer %s returned %s", addr, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (t *HTTPTransport) client() *http.Client {
+	if t.Client != nil {
+		return t.Client
+	}
+	return http.DefaultClient
+}
@@ -0,0 +1,12 @@
+package ttlcache
+
+import "errors"
+
+var (
+	// ErrClosed is returned when an operation is attempted on a cache that has
+	// already been closed via Close().
+	ErrClosed = errors.New("cache is closed")
+	// ErrNotFound is returned by Get and Remove when the requested key is not
+	// present in the cache.
+	ErrNotFound = errors.New("key not found")
+)
@@ -0,0 +1,60 @@
+package ttlcache
+
+import (
+	"container/list"
+	"time"
+)
+
+const (
+	// ItemNotExpire makes an item never expire, regardless of the cache's
+	// global TTL.
+	ItemNotExpire time.Duration = -1
+	// ItemExpireWithGlobalTTL makes an item follow the cache's global TTL, as
+	// set by SetTTL, instead of using a TTL of its own.
+	ItemExpireWithGlobalTTL time.Duration = 0
+)
+
+// item is a single cache entry. wheelList and wheelElem track its place in
+// the timingWheel's bucket (nil when the item isn't scheduled in the
+// wheel), letting the wheel remove or reschedule it in O(1).
+type item[K comparable, V any] struct {
+	key       K
+	data      V
+	ttl       time.Duration
+	touchedAt time.Time
+	expireAt  time.Time
+
+	wheelList *list.List
+	wheelElem *list.Element
+}
+
+func newItem[K comparable, V any](key K, data V, ttl time.Duration, globalTTL time.Duration) *item[K, V] {
+	it := &item[K, V]{
+		key:  key,
+		data: data,
+		ttl:  ttl,
+	}
+	it.touch(globalTTL)
+	return it
+}
+
+// touch resets the item's expiration clock to "now", using globalTTL when the
+// item itself was created with ItemExpireWithGlobalTTL.
+func (it *item[K, V]) touch(globalTTL time.Duration) {
+	it.touchedAt = time.Now()
+
+	ttl := it.ttl
+	if ttl == ItemExpireWithGlobalTTL {
+		ttl = globalTTL
+	}
+
+	if ttl <= ItemExpireWithGlobalTTL {
+		// ItemNotExpire, or an unset (zero) global TTL: both mean never
+		// expire, rather than expiring immediately. Far enough in the
+		// future to always land in the timing wheel's coarsest, farthest
+		// bucket, without needing to special-case a zero Time.
+		it.expireAt = it.touchedAt.Add(100 * 365 * 24 * time.Hour)
+		return
+	}
+	it.expireAt = it.touchedAt.Add(ttl)
+}
@@ -0,0 +1,74 @@
+package ttlcache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// ContextLoaderFunction is like LoaderFunction, but receives the context
+// passed to GetWithContext (context.Background() for plain Get calls), so
+// it can abort its work once that context is done. See
+// SetContextLoaderFunction.
+type ContextLoaderFunction[K comparable, V any] func(ctx context.Context, key K) (data V, ttl time.Duration, err error)
+
+// negativeEntry is a loader error cached under a key for negativeCacheTTL,
+// so repeated misses don't stampede a failing loader. See
+// SetNegativeCacheTTL.
+type negativeEntry struct {
+	err       error
+	expiresAt time.Time
+}
+
+// loaderOptions holds the per-call settings a LoaderOption can override.
+type loaderOptions struct {
+	skipNegativeCache bool
+}
+
+// LoaderOption customizes a single GetWithContext call. See
+// WithSkipNegativeCache.
+type LoaderOption func(*loaderOptions)
+
+// WithSkipNegativeCache makes this call neither read nor populate the
+// negative cache, even if SetNegativeCacheTTL is set.
+func WithSkipNegativeCache() LoaderOption {
+	return func(o *loaderOptions) {
+		o.skipNegativeCache = true
+	}
+}
+
+// SetContextLoaderFunction is like SetLoaderFunction, but loader receives
+// the context passed to GetWithContext, letting it cancel its work once
+// every waiter sharing its in-flight call has given up.
+func (c *Cache[K, V]) SetContextLoaderFunction(loader ContextLoaderFunction[K, V]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.loaderFunction = loader
+}
+
+// SetNegativeCacheTTL makes a loader error (including ErrNotFound) cached
+// for d, so repeated Gets for the same key return the cached error instead
+// of stampeding a failing loader. A duration of 0 (the default) disables
+// negative caching. Pass WithSkipNegativeCache to GetWithContext to opt a
+// single call out.
+func (c *Cache[K, V]) SetNegativeCacheTTL(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.negativeCacheTTL = d
+}
+
+// join registers ctx as an additional waiter on call, cancelling call's
+// own context once ctx and every other registered waiter's context have
+// all finished, or once call itself finishes (whichever comes first).
+func (call *loaderCall[V]) join(ctx context.Context) {
+	atomic.AddInt32(&call.waiters, 1)
+	go func() {
+		select {
+		case <-ctx.Done():
+			if atomic.AddInt32(&call.waiters, -1) == 0 {
+				call.cancel()
+			}
+		case <-call.done:
+		}
+	}()
+}
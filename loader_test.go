@@ -0,0 +1,80 @@
+package ttlcache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/witchu/ttlcache/v2"
+)
+
+// GetWithContext passes its ctx through to a ContextLoaderFunction set via
+// SetContextLoaderFunction.
+func TestCache_GetWithContext_PassesContext(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCacheOf[string, string]()
+	defer cache.Close()
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "marker")
+
+	var seen interface{}
+	cache.SetContextLoaderFunction(func(ctx context.Context, key string) (string, time.Duration, error) {
+		seen = ctx.Value(ctxKey{})
+		return "data", 0, nil
+	})
+
+	value, err := cache.GetWithContext(ctx, "1")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "data", value)
+	assert.Equal(t, "marker", seen)
+}
+
+// A loader error is cached for SetNegativeCacheTTL, so a second Get for the
+// same key doesn't call the loader again.
+func TestCache_SetNegativeCacheTTL(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCacheOf[string, string]()
+	defer cache.Close()
+
+	var calls int
+	cache.SetNegativeCacheTTL(time.Hour)
+	cache.SetLoaderFunction(func(key string) (string, time.Duration, error) {
+		calls++
+		return "", 0, ErrNotFound
+	})
+
+	_, err := cache.Get("missing")
+	assert.Equal(t, ErrNotFound, err)
+
+	_, err = cache.Get("missing")
+	assert.Equal(t, ErrNotFound, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, int64(1), cache.GetMetrics().NegativeCacheHits)
+}
+
+// WithSkipNegativeCache opts a single call out of both reading and
+// populating the negative cache.
+func TestCache_WithSkipNegativeCache(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCacheOf[string, string]()
+	defer cache.Close()
+
+	var calls int
+	cache.SetNegativeCacheTTL(time.Hour)
+	cache.SetLoaderFunction(func(key string) (string, time.Duration, error) {
+		calls++
+		return "", 0, ErrNotFound
+	})
+
+	_, err := cache.GetWithContext(context.Background(), "missing", WithSkipNegativeCache())
+	assert.Equal(t, ErrNotFound, err)
+
+	_, err = cache.GetWithContext(context.Background(), "missing", WithSkipNegativeCache())
+	assert.Equal(t, ErrNotFound, err)
+	assert.Equal(t, 2, calls)
+}
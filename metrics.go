@@ -0,0 +1,22 @@
+package ttlcache
+
+// Metrics describes a point-in-time snapshot of a Cache's activity, as
+// returned by Cache.GetMetrics.
+type Metrics struct {
+	// Inserted is the number of items added via Set/SetWithTTL.
+	Inserted int64
+	// Evicted is the number of items that have left the cache, for any
+	// reason (expiry, size eviction, explicit removal or purge).
+	Evicted int64
+	// Hits is the number of items currently held in the cache.
+	Hits int64
+	// Misses is the number of Get calls for a key that was not present,
+	// not counting those served from the negative cache.
+	Misses int64
+	// Retrievals is the number of Get calls that found their key.
+	Retrievals int64
+	// NegativeCacheHits is the number of Get calls served from the negative
+	// cache (a loader error cached via SetNegativeCacheTTL) instead of the
+	// loader.
+	NegativeCacheHits int64
+}
@@ -0,0 +1,146 @@
+package ttlcache
+
+import "container/list"
+
+// Policy decides which key SetCacheSizeLimit should evict next once the
+// cache is over its limit. Implementations are consulted through OnAdd,
+// OnAccess and OnRemove so they can track whatever bookkeeping they need,
+// and through Victim when the cache actually has to make room.
+//
+// The cache calls into its Policy only while holding its own mutex, so a
+// Policy used by a single Cache needs no locking of its own; the policies
+// in this package have none.
+type Policy[K comparable] interface {
+	// OnAdd is called once, right after a brand new key is inserted.
+	OnAdd(key K)
+	// OnAccess is called whenever an existing key is read or refreshed.
+	OnAccess(key K)
+	// OnRemove is called when a key leaves the cache, for any reason, so
+	// the policy can drop its bookkeeping for it.
+	OnRemove(key K)
+	// Victim returns the key the policy would like to evict next. ok is
+	// false if the policy has nothing to evict.
+	Victim() (key K, ok bool)
+}
+
+// fifoPolicy evicts keys in the order they were first added, ignoring
+// access. It is the cache's default policy and reproduces the historical
+// SetCacheSizeLimit behavior.
+type fifoPolicy[K comparable] struct {
+	order *list.List
+	nodes map[K]*list.Element
+}
+
+// NewFIFOPolicy returns a Policy that evicts the oldest-inserted key first.
+func NewFIFOPolicy[K comparable]() Policy[K] {
+	return &fifoPolicy[K]{
+		order: list.New(),
+		nodes: make(map[K]*list.Element),
+	}
+}
+
+func (p *fifoPolicy[K]) OnAdd(key K) {
+	if _, found := p.nodes[key]; found {
+		return
+	}
+	p.nodes[key] = p.order.PushBack(key)
+}
+
+func (p *fifoPolicy[K]) OnAccess(key K) {}
+
+func (p *fifoPolicy[K]) OnRemove(key K) {
+	if e, found := p.nodes[key]; found {
+		p.order.Remove(e)
+		delete(p.nodes, key)
+	}
+}
+
+func (p *fifoPolicy[K]) Victim() (K, bool) {
+	e := p.order.Front()
+	if e == nil {
+		var zero K
+		return zero, false
+	}
+	return e.Value.(K), true
+}
+
+// lruPolicy evicts the least-recently-used key: OnAdd and OnAccess both
+// move the key to the most-recently-used end.
+type lruPolicy[K comparable] struct {
+	order *list.List
+	nodes map[K]*list.Element
+}
+
+// NewLRUPolicy returns a Policy that evicts the least-recently-used key.
+func NewLRUPolicy[K comparable]() Policy[K] {
+	return &lruPolicy[K]{
+		order: list.New(),
+		nodes: make(map[K]*list.Element),
+	}
+}
+
+func (p *lruPolicy[K]) OnAdd(key K) {
+	p.OnAccess(key)
+}
+
+func (p *lruPolicy[K]) OnAccess(key K) {
+	if e, found := p.nodes[key]; found {
+		p.order.MoveToBack(e)
+		return
+	}
+	p.nodes[key] = p.order.PushBack(key)
+}
+
+func (p *lruPolicy[K]) OnRemove(key K) {
+	if e, found := p.nodes[key]; found {
+		p.order.Remove(e)
+		delete(p.nodes, key)
+	}
+}
+
+func (p *lruPolicy[K]) Victim() (K, bool) {
+	e := p.order.Front()
+	if e == nil {
+		var zero K
+		return zero, false
+	}
+	return e.Value.(K), true
+}
+
+// lfuPolicy evicts the key with the fewest accesses. Victim is O(n) in the
+// number of tracked keys, which is fine at the sizes SetCacheSizeLimit is
+// meant for; ties are broken arbitrarily.
+type lfuPolicy[K comparable] struct {
+	freq map[K]int64
+}
+
+// NewLFUPolicy returns a Policy that evicts the least-frequently-used key.
+func NewLFUPolicy[K comparable]() Policy[K] {
+	return &lfuPolicy[K]{freq: make(map[K]int64)}
+}
+
+func (p *lfuPolicy[K]) OnAdd(key K) {
+	if _, found := p.freq[key]; !found {
+		p.freq[key] = 0
+	}
+}
+
+func (p *lfuPolicy[K]) OnAccess(key K) {
+	p.freq[key]++
+}
+
+func (p *lfuPolicy[K]) OnRemove(key K) {
+	delete(p.freq, key)
+}
+
+func (p *lfuPolicy[K]) Victim() (K, bool) {
+	var victim K
+	var min int64
+	var found bool
+	for key, count := range p.freq {
+		if !found || count < min {
+			victim, min, found = key, count, true
+		}
+	}
+	return victim, found
+}
@@ -0,0 +1,86 @@
+package ttlcache_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/witchu/ttlcache/v2"
+)
+
+// SetEvictionPolicy lets a size-limited cache evict by recency instead of
+// insertion order.
+func TestCache_SetEvictionPolicy_LRU(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache()
+	defer cache.Close()
+
+	cache.SetTTL(time.Hour)
+	cache.SetEvictionPolicy(NewLRUPolicy[string]())
+	cache.SetCacheSizeLimit(3)
+
+	cache.Set("a", "1")
+	cache.Set("b", "2")
+	cache.Set("c", "3")
+
+	// Keep "a" alive by reading it before the cache grows again.
+	cache.Get("a")
+
+	cache.Set("d", "4")
+
+	assert.Equal(t, 3, cache.Count())
+	_, err := cache.Get("a")
+	assert.Equal(t, nil, err, "recently accessed key should survive eviction")
+	_, err = cache.Get("b")
+	assert.Equal(t, ErrNotFound, err, "least-recently-used key should have been evicted")
+}
+
+// A size-limited eviction still reports EvictedSize through
+// SetExpirationReasonCallback when a non-default Policy is in use.
+func TestCache_SetEvictionPolicy_ReportsEvictedSizeReason(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache()
+	defer cache.Close()
+
+	var reason EvictionReason
+	// Buffered so the callback never blocks: besides the EvictedSize
+	// eviction under test, the deferred cache.Close() fires it again (with
+	// reason Closed) for the surviving key, which nothing reads.
+	sync := make(chan struct{}, 2)
+	cache.SetExpirationReasonCallback(func(key string, evReason EvictionReason, value interface{}) {
+		reason = evReason
+		sync <- struct{}{}
+	})
+
+	cache.SetTTL(time.Hour)
+	cache.SetEvictionPolicy(NewLFUPolicy[string]())
+	cache.SetCacheSizeLimit(1)
+
+	cache.Set("one", "1")
+	cache.Set("two", "2")
+	<-sync
+
+	assert.Equal(t, EvictedSize, reason)
+}
+
+// W-TinyLFU should reject a one-off key in favor of keeping a key that has
+// already been seen more than once.
+func TestWTinyLFUPolicy_RejectsOneHitWonder(t *testing.T) {
+	t.Parallel()
+
+	policy := NewWTinyLFUPolicy[string](4096)
+
+	policy.OnAdd("hot")
+	for i := 0; i < 5; i++ {
+		policy.OnAccess("hot")
+	}
+
+	policy.OnAdd("cold")
+
+	victim, ok := policy.Victim()
+	assert.True(t, ok)
+	assert.Equal(t, "cold", victim, fmt.Sprintf("expected the one-hit-wonder to be rejected, got %q", victim))
+}
@@ -0,0 +1,99 @@
+package ttlcache
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// promCollector holds the Prometheus metrics RegisterPrometheus publishes.
+// It is kept separate from Cache's K/V type parameters since a
+// prometheus.Registerer has no notion of them.
+type promCollector struct {
+	hits              prometheus.Counter
+	misses            prometheus.Counter
+	inserted          prometheus.Counter
+	evicted           *prometheus.CounterVec
+	loaderLatency     prometheus.Histogram
+	ttlAtEviction     prometheus.Histogram
+	negativeCacheHits prometheus.Counter
+}
+
+func newPromCollector(reg prometheus.Registerer, namespace string) *promCollector {
+	c := &promCollector{
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_hits_total",
+			Help:      "Number of Get calls that found their key.",
+		}),
+		misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_misses_total",
+			Help:      "Number of Get calls for a key that was not present, not counting negative cache hits.",
+		}),
+		inserted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_inserted_total",
+			Help:      "Number of items added via Set/SetWithTTL.",
+		}),
+		evicted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_evicted_total",
+			Help:      "Number of items that have left the cache, by EvictionReason.",
+		}, []string{"reason"}),
+		loaderLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cache_loader_latency_seconds",
+			Help:      "Time spent waiting on the loader function for a Get miss.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		ttlAtEviction: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cache_ttl_remaining_seconds",
+			Help:      "TTL an item still had left at the moment it was evicted.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		negativeCacheHits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_negative_cache_hits_total",
+			Help:      "Number of Get calls served from the negative cache instead of the loader.",
+		}),
+	}
+	reg.MustRegister(c.hits, c.misses, c.inserted, c.evicted, c.loaderLatency, c.ttlAtEviction, c.negativeCacheHits)
+	return c
+}
+
+func (c *promCollector) observeEviction(reason EvictionReason, ttlRemaining time.Duration) {
+	c.evicted.WithLabelValues(reasonLabel(reason)).Inc()
+	if ttlRemaining > 0 {
+		c.ttlAtEviction.Observe(ttlRemaining.Seconds())
+	}
+}
+
+func reasonLabel(reason EvictionReason) string {
+	switch reason {
+	case Expired:
+		return "expired"
+	case EvictedSize:
+		return "evicted_size"
+	case Removed:
+		return "removed"
+	case Closed:
+		return "closed"
+	case NegativeCacheExpired:
+		return "negative_cache_expired"
+	default:
+		return "unknown"
+	}
+}
+
+// RegisterPrometheus publishes the cache's activity to reg as Prometheus
+// metrics namespaced under namespace: counters for hits, misses, inserts
+// and evictions (broken down by EvictionReason), and histograms for loader
+// latency and the TTL an item still had left when it was evicted.
+func (c *Cache[K, V]) RegisterPrometheus(reg prometheus.Registerer, namespace string) {
+	collector := newPromCollector(reg, namespace)
+	c.mutex.Lock()
+	c.collector = collector
+	c.mutex.Unlock()
+}
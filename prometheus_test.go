@@ -0,0 +1,45 @@
+package ttlcache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	. "github.com/witchu/ttlcache/v2"
+)
+
+// RegisterPrometheus publishes hits, misses and evictions-by-reason as
+// Prometheus metrics.
+func TestCache_RegisterPrometheus(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache()
+	defer cache.Close()
+
+	reg := prometheus.NewRegistry()
+	cache.RegisterPrometheus(reg, "ttlcache_test")
+
+	cache.SetTTL(time.Hour)
+	cache.Set("a", "1")
+	cache.Get("a")
+	cache.Get("missing")
+	assert.Equal(t, nil, cache.Remove("a"))
+
+	families, err := reg.Gather()
+	assert.Equal(t, nil, err)
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if counter := metric.GetCounter(); counter != nil {
+				values[family.GetName()] += counter.GetValue()
+			}
+		}
+	}
+
+	assert.Equal(t, float64(1), values["ttlcache_test_cache_hits_total"])
+	assert.Equal(t, float64(1), values["ttlcache_test_cache_misses_total"])
+	assert.Equal(t, float64(1), values["ttlcache_test_cache_inserted_total"])
+	assert.Equal(t, float64(1), values["ttlcache_test_cache_evicted_total"])
+}
@@ -0,0 +1,117 @@
+package ttlcache
+
+import "time"
+
+// Store lets a Cache persist its entries outside process memory: entries
+// survive a restart, and several processes can share state through the
+// same backing Store. Wire one in with SetStore. expiresAt is the zero
+// Time for an entry that does not expire.
+type Store[K comparable, V any] interface {
+	Get(key K) (data V, expiresAt time.Time, err error)
+	Set(key K, data V, expiresAt time.Time) error
+	Delete(key K) error
+	// Iterate calls fn for every entry in the store, stopping early if fn
+	// returns false.
+	Iterate(fn func(key K, data V, expiresAt time.Time) bool)
+}
+
+// WriteMode controls how Set/SetWithTTL propagate to the Store set via
+// SetStore.
+type WriteMode int
+
+const (
+	// WriteThrough writes to the Store synchronously, before Set/SetWithTTL
+	// return. It is the default.
+	WriteThrough WriteMode = iota
+	// WriteBack queues the write and flushes it from a background
+	// goroutine, trading durability guarantees for a Set/SetWithTTL call
+	// that never blocks on the Store.
+	WriteBack
+)
+
+// writeBackEntry is one pending write queued by WriteBack mode.
+type writeBackEntry[K comparable, V any] struct {
+	store    Store[K, V]
+	key      K
+	data     V
+	expireAt time.Time
+}
+
+// SetStore wires the cache to a persistent Store. By default every write
+// goes through it synchronously (WriteThrough) and misses still fall
+// through to the loader function (ReadThrough disabled); see SetWriteMode
+// and SetReadThrough.
+func (c *Cache[K, V]) SetStore(store Store[K, V]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.store = store
+	if store != nil && c.writeBackQueue == nil {
+		c.writeBackQueue = make(chan writeBackEntry[K, V], 256)
+		go c.runWriteBack()
+	}
+}
+
+// SetWriteMode selects how Set/SetWithTTL propagate to the Store set via
+// SetStore.
+func (c *Cache[K, V]) SetWriteMode(mode WriteMode) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.writeMode = mode
+}
+
+// SetReadThrough controls whether a Get miss consults the Store set via
+// SetStore before falling back to the loader function set via
+// SetLoaderFunction.
+func (c *Cache[K, V]) SetReadThrough(enabled bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.readThrough = enabled
+}
+
+// WarmFromStore populates the cache from every entry in the Store set via
+// SetStore, letting the cache survive a process restart.
+func (c *Cache[K, V]) WarmFromStore() error {
+	c.mutex.Lock()
+	store := c.store
+	closed := c.isClosed
+	c.mutex.Unlock()
+	if closed {
+		return ErrClosed
+	}
+	if store == nil {
+		return nil
+	}
+
+	store.Iterate(func(key K, data V, expiresAt time.Time) bool {
+		ttl := ItemNotExpire
+		if !expiresAt.IsZero() {
+			if ttl = time.Until(expiresAt); ttl <= 0 {
+				return true
+			}
+		}
+		c.mutex.Lock()
+		if !c.isClosed {
+			c.setWithTTL(key, data, ttl)
+		}
+		c.mutex.Unlock()
+		return true
+	})
+	return nil
+}
+
+// runWriteBack flushes queued WriteBack writes until the cache is closed.
+func (c *Cache[K, V]) runWriteBack() {
+	for entry := range c.writeBackQueue {
+		_ = entry.store.Set(entry.key, entry.data, entry.expireAt)
+	}
+}
+
+// queueWriteBack enqueues entry on queue, dropping it if the queue is
+// full rather than blocking the caller: WriteBack is already a
+// best-effort, eventually-consistent mode.
+func queueWriteBack[K comparable, V any](queue chan writeBackEntry[K, V], entry writeBackEntry[K, V]) {
+	select {
+	case queue <- entry:
+	default:
+	}
+}
@@ -0,0 +1,116 @@
+// Package boltstore implements ttlcache.Store[string, []byte] backed by a
+// BoltDB file, for single-process persistence across restarts.
+package boltstore
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/witchu/ttlcache/v2"
+	bolt "go.etcd.io/bbolt"
+)
+
+var bucketName = []byte("ttlcache")
+
+// Store is a ttlcache.Store[string, []byte] backed by a BoltDB file. Each
+// value is stored alongside its expiry as a big-endian Unix nanosecond
+// timestamp prefix, since Bolt itself has no notion of expiration.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens (creating if needed) the BoltDB file at path for use as a
+// Store.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketName)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying BoltDB file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Get implements ttlcache.Store.
+func (s *Store) Get(key string) ([]byte, time.Time, error) {
+	var data []byte
+	var expiresAt time.Time
+	err := s.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(bucketName).Get([]byte(key))
+		if raw == nil {
+			return ttlcache.ErrNotFound
+		}
+		var ok bool
+		if expiresAt, data, ok = decode(raw); !ok {
+			return ttlcache.ErrNotFound
+		}
+		return nil
+	})
+	return data, expiresAt, err
+}
+
+// Set implements ttlcache.Store.
+func (s *Store) Set(key string, data []byte, expiresAt time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Put([]byte(key), encode(expiresAt, data))
+	})
+}
+
+// Delete implements ttlcache.Store.
+func (s *Store) Delete(key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Delete([]byte(key))
+	})
+}
+
+// Iterate implements ttlcache.Store.
+func (s *Store) Iterate(fn func(key string, data []byte, expiresAt time.Time) bool) {
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(bucketName).Cursor()
+		for k, raw := cursor.First(); k != nil; k, raw = cursor.Next() {
+			expiresAt, data, ok := decode(raw)
+			if !ok {
+				continue
+			}
+			if !fn(string(k), data, expiresAt) {
+				break
+			}
+		}
+		return nil
+	})
+}
+
+// encode prepends expiresAt (0 for never) as a big-endian Unix nanosecond
+// timestamp, so a single Bolt value carries both the data and its expiry.
+func encode(expiresAt time.Time, data []byte) []byte {
+	var nanos int64
+	if !expiresAt.IsZero() {
+		nanos = expiresAt.UnixNano()
+	}
+	buf := make([]byte, 8+len(data))
+	binary.BigEndian.PutUint64(buf, uint64(nanos))
+	copy(buf[8:], data)
+	return buf
+}
+
+func decode(raw []byte) (time.Time, []byte, bool) {
+	if len(raw) < 8 {
+		return time.Time{}, nil, false
+	}
+	nanos := int64(binary.BigEndian.Uint64(raw))
+	var expiresAt time.Time
+	if nanos != 0 {
+		expiresAt = time.Unix(0, nanos)
+	}
+	return expiresAt, raw[8:], true
+}
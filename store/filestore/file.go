@@ -0,0 +1,112 @@
+// Package filestore implements ttlcache.Store[string, []byte] backed by a
+// directory of files, one per key, for simple single-machine persistence
+// with no extra dependencies.
+package filestore
+
+import (
+	"encoding/binary"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/witchu/ttlcache/v2"
+)
+
+// Store is a ttlcache.Store[string, []byte] that keeps one file per key
+// under dir, named after the key's URL-escaped form so arbitrary key
+// strings are safe path components.
+type Store struct {
+	dir string
+}
+
+// New returns a Store rooted at dir, creating it if necessary.
+func New(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key))
+}
+
+// Get implements ttlcache.Store.
+func (s *Store) Get(key string) ([]byte, time.Time, error) {
+	raw, err := os.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, time.Time{}, ttlcache.ErrNotFound
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	expiresAt, data, ok := decode(raw)
+	if !ok {
+		return nil, time.Time{}, ttlcache.ErrNotFound
+	}
+	return data, expiresAt, nil
+}
+
+// Set implements ttlcache.Store.
+func (s *Store) Set(key string, data []byte, expiresAt time.Time) error {
+	return os.WriteFile(s.path(key), encode(expiresAt, data), 0600)
+}
+
+// Delete implements ttlcache.Store.
+func (s *Store) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Iterate implements ttlcache.Store.
+func (s *Store) Iterate(fn func(key string, data []byte, expiresAt time.Time) bool) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		key, err := url.PathUnescape(entry.Name())
+		if err != nil {
+			continue
+		}
+		data, expiresAt, err := s.Get(key)
+		if err != nil {
+			continue
+		}
+		if !fn(key, data, expiresAt) {
+			return
+		}
+	}
+}
+
+// encode prepends expiresAt (0 for never) as a big-endian Unix nanosecond
+// timestamp, so a single file carries both the data and its expiry.
+func encode(expiresAt time.Time, data []byte) []byte {
+	var nanos int64
+	if !expiresAt.IsZero() {
+		nanos = expiresAt.UnixNano()
+	}
+	buf := make([]byte, 8+len(data))
+	binary.BigEndian.PutUint64(buf, uint64(nanos))
+	copy(buf[8:], data)
+	return buf
+}
+
+func decode(raw []byte) (time.Time, []byte, bool) {
+	if len(raw) < 8 {
+		return time.Time{}, nil, false
+	}
+	nanos := int64(binary.BigEndian.Uint64(raw))
+	var expiresAt time.Time
+	if nanos != 0 {
+		expiresAt = time.Unix(0, nanos)
+	}
+	return expiresAt, raw[8:], true
+}
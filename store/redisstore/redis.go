@@ -0,0 +1,80 @@
+// Package redisstore implements ttlcache.Store[string, []byte] backed by
+// Redis, letting a Cache survive restarts and share state across
+// processes.
+package redisstore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/witchu/ttlcache/v2"
+)
+
+// Store is a ttlcache.Store[string, []byte] backed by a Redis instance.
+// Keys are namespaced under prefix, and an entry's TTL is tracked by
+// Redis's own expiry rather than stored alongside the value.
+type Store struct {
+	client *redis.Client
+	prefix string
+}
+
+// New wraps client, namespacing every key under prefix ("" for none).
+func New(client *redis.Client, prefix string) *Store {
+	return &Store{client: client, prefix: prefix}
+}
+
+// Get implements ttlcache.Store.
+func (s *Store) Get(key string) ([]byte, time.Time, error) {
+	ctx := context.Background()
+	data, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, time.Time{}, ttlcache.ErrNotFound
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	ttl, err := s.client.TTL(ctx, s.prefix+key).Result()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	return data, expiresAt, nil
+}
+
+// Set implements ttlcache.Store.
+func (s *Store) Set(key string, data []byte, expiresAt time.Time) error {
+	var ttl time.Duration
+	if !expiresAt.IsZero() {
+		if ttl = time.Until(expiresAt); ttl <= 0 {
+			return s.Delete(key)
+		}
+	}
+	return s.client.Set(context.Background(), s.prefix+key, data, ttl).Err()
+}
+
+// Delete implements ttlcache.Store.
+func (s *Store) Delete(key string) error {
+	return s.client.Del(context.Background(), s.prefix+key).Err()
+}
+
+// Iterate implements ttlcache.Store.
+func (s *Store) Iterate(fn func(key string, data []byte, expiresAt time.Time) bool) {
+	ctx := context.Background()
+	iter := s.client.Scan(ctx, 0, s.prefix+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		key := strings.TrimPrefix(iter.Val(), s.prefix)
+		data, expiresAt, err := s.Get(key)
+		if err != nil {
+			continue
+		}
+		if !fn(key, data, expiresAt) {
+			return
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package ttlcache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/witchu/ttlcache/v2"
+)
+
+// memoryStore is a minimal Store[string, string] used to exercise Cache's
+// Store wiring without a real backend. It is safe for concurrent use since
+// WriteBack mode flushes from a background goroutine.
+type memoryStore struct {
+	mu   sync.Mutex
+	data map[string]string
+	exp  map[string]time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{data: map[string]string{}, exp: map[string]time.Time{}}
+}
+
+func (s *memoryStore) Get(key string) (string, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, found := s.data[key]
+	if !found {
+		return "", time.Time{}, ErrNotFound
+	}
+	return data, s.exp[key], nil
+}
+
+func (s *memoryStore) Set(key string, data string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = data
+	s.exp[key] = expiresAt
+	return nil
+}
+
+func (s *memoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	delete(s.exp, key)
+	return nil
+}
+
+func (s *memoryStore) Iterate(fn func(key string, data string, expiresAt time.Time) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, data := range s.data {
+		if !fn(key, data, s.exp[key]) {
+			return
+		}
+	}
+}
+
+// WriteThrough mode (the default) writes to the Store before Set returns.
+func TestCache_SetStore_WriteThrough(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	cache := NewCacheOf[string, string]()
+	defer cache.Close()
+
+	cache.SetStore(store)
+	cache.SetTTL(time.Hour)
+	assert.Equal(t, nil, cache.Set("a", "1"))
+
+	data, _, err := store.Get("a")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "1", data)
+}
+
+// WriteBack mode queues the write and flushes it asynchronously, so it
+// reaches the Store shortly after Set returns rather than before.
+func TestCache_SetStore_WriteBack(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	cache := NewCacheOf[string, string]()
+	defer cache.Close()
+
+	cache.SetStore(store)
+	cache.SetWriteMode(WriteBack)
+	cache.SetTTL(time.Hour)
+	assert.Equal(t, nil, cache.Set("a", "1"))
+
+	time.Sleep(10 * time.Millisecond)
+	data, _, err := store.Get("a")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "1", data)
+}
+
+// ReadThrough lets a Get miss be satisfied from the Store, without going
+// through the loader function.
+func TestCache_SetReadThrough(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	store.data["warm"] = "already-there"
+	store.exp["warm"] = time.Now().Add(time.Hour)
+
+	cache := NewCacheOf[string, string]()
+	defer cache.Close()
+	cache.SetStore(store)
+	cache.SetReadThrough(true)
+	cache.SetLoaderFunction(func(key string) (string, time.Duration, error) {
+		t.Fatal("loader should not run for a key the store already has")
+		return "", 0, nil
+	})
+
+	value, err := cache.Get("warm")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "already-there", value)
+}
@@ -0,0 +1,166 @@
+package ttlcache
+
+import (
+	"container/list"
+	"time"
+)
+
+// wheelTick is the resolution the background expiration goroutine ticks at,
+// matching the finest (millisecond) wheel level.
+const wheelTick = time.Millisecond
+
+// wheelLevelBuckets is the bucket count of each level of the hierarchical
+// timing wheel, finest first. An item is placed in the finest level whose
+// total span (tick * buckets) still covers its remaining TTL; an item that
+// outlives even the coarsest level's span is parked in that level's
+// farthest bucket and re-evaluated (and, if still not due, re-parked) the
+// next time that bucket cascades.
+//
+// Level 0 ticks every wheelTick; each coarser level's tick is derived (see
+// newTimingWheel) from the span of the level below it, since that's the
+// actual cadence at which advance turns its cursor.
+var wheelLevelBuckets = [3]int{512, 60, 60}
+
+// wheelLevel is one level of the hierarchical timing wheel: a ring of
+// doubly-linked-list buckets, one per tick, so inserting, removing and
+// rescheduling an item are all O(1).
+type wheelLevel[K comparable, V any] struct {
+	tick    time.Duration
+	buckets []*list.List
+	cursor  int
+}
+
+func newWheelLevel[K comparable, V any](tick time.Duration, size int) *wheelLevel[K, V] {
+	buckets := make([]*list.List, size)
+	for i := range buckets {
+		buckets[i] = list.New()
+	}
+	return &wheelLevel[K, V]{tick: tick, buckets: buckets}
+}
+
+func (l *wheelLevel[K, V]) span() time.Duration {
+	return l.tick * time.Duration(len(l.buckets))
+}
+
+// timingWheel tracks every item's expiration across cascading levels of
+// increasingly coarse ticks (1ms, 512ms and 30.72s), replacing the
+// single-timer-over-a-priority-queue design that needed an O(log n)
+// heap.Fix on every Set and Get (with TTL extension) and a timer reset on
+// every change to the soonest-expiring item.
+type timingWheel[K comparable, V any] struct {
+	levels [len(wheelLevelBuckets)]*wheelLevel[K, V]
+}
+
+func newTimingWheel[K comparable, V any]() *timingWheel[K, V] {
+	w := &timingWheel[K, V]{}
+	tick := wheelTick
+	for i, buckets := range wheelLevelBuckets {
+		w.levels[i] = newWheelLevel[K, V](tick, buckets)
+		tick = w.levels[i].span()
+	}
+	return w
+}
+
+// schedule (re)inserts it according to its current expireAt, computed
+// against now. It must not already be in the wheel.
+func (w *timingWheel[K, V]) schedule(it *item[K, V], now time.Time) {
+	d := it.expireAt.Sub(now)
+	if d < 0 {
+		d = 0
+	}
+
+	for i, level := range w.levels {
+		if d >= level.span() && i != len(w.levels)-1 {
+			continue
+		}
+		// At least 1, so an already-due (or sub-tick) item lands in the
+		// next tick of this level instead of its current one, which
+		// advance is about to step past without visiting again until a
+		// full revolution of the level.
+		ticksAhead := int(d / level.tick)
+		if ticksAhead < 1 {
+			ticksAhead = 1
+		}
+		if ticksAhead >= len(level.buckets) {
+			ticksAhead = len(level.buckets) - 1
+		}
+		slot := (level.cursor + ticksAhead) % len(level.buckets)
+		it.wheelList = level.buckets[slot]
+		it.wheelElem = it.wheelList.PushBack(it)
+		return
+	}
+}
+
+// remove detaches it from whichever bucket holds it, if any.
+func (w *timingWheel[K, V]) remove(it *item[K, V]) {
+	if it.wheelList == nil {
+		return
+	}
+	it.wheelList.Remove(it.wheelElem)
+	it.wheelList, it.wheelElem = nil, nil
+}
+
+// reschedule removes it and schedules it again, for a TTL change or a hit
+// that extends the TTL.
+func (w *timingWheel[K, V]) reschedule(it *item[K, V], now time.Time) {
+	w.remove(it)
+	w.schedule(it, now)
+}
+
+// advance runs one millisecond tick: it fires (or re-parks) every item due
+// in the current bucket of the finest level, and cascades a level's
+// current bucket down into finer levels whenever the level below it has
+// just completed a full revolution.
+func (w *timingWheel[K, V]) advance(now time.Time, fire func(it *item[K, V])) {
+	ms := w.levels[0]
+	ms.cursor = (ms.cursor + 1) % len(ms.buckets)
+	w.drain(ms.buckets[ms.cursor], now, fire)
+
+	if ms.cursor != 0 {
+		return
+	}
+	sec := w.levels[1]
+	sec.cursor = (sec.cursor + 1) % len(sec.buckets)
+	w.cascade(sec.buckets[sec.cursor], now)
+
+	if sec.cursor != 0 {
+		return
+	}
+	min := w.levels[2]
+	min.cursor = (min.cursor + 1) % len(min.buckets)
+	w.cascade(min.buckets[min.cursor], now)
+}
+
+// drain empties bucket, firing each item whose expireAt has actually
+// passed and rescheduling any other (the bucket assignment is only ever
+// approximate for cascaded long-TTL items parked in a wheel's farthest
+// bucket).
+func (w *timingWheel[K, V]) drain(bucket *list.List, now time.Time, fire func(it *item[K, V])) {
+	for e := bucket.Front(); e != nil; {
+		next := e.Next()
+		it := e.Value.(*item[K, V])
+		bucket.Remove(e)
+		it.wheelList, it.wheelElem = nil, nil
+
+		if it.expireAt.After(now) {
+			w.schedule(it, now)
+		} else {
+			fire(it)
+		}
+		e = next
+	}
+}
+
+// cascade empties bucket, rescheduling every item into whichever level is
+// now appropriate for its remaining time (often, but not always, a finer
+// one than the level bucket held).
+func (w *timingWheel[K, V]) cascade(bucket *list.List, now time.Time) {
+	for e := bucket.Front(); e != nil; {
+		next := e.Next()
+		it := e.Value.(*item[K, V])
+		bucket.Remove(e)
+		it.wheelList, it.wheelElem = nil, nil
+		w.schedule(it, now)
+		e = next
+	}
+}
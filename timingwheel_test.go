@@ -0,0 +1,44 @@
+package ttlcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// schedule puts an item into a bucket of the wheel, and remove detaches it
+// again.
+func TestTimingWheel_ScheduleAndRemove(t *testing.T) {
+	now := time.Now()
+	w := newTimingWheel[string, string]()
+
+	it := newItem("a", "1", time.Millisecond*10, 0)
+	it.expireAt = now.Add(10 * time.Millisecond)
+	w.schedule(it, now)
+	assert.NotNil(t, it.wheelList)
+
+	w.remove(it)
+	assert.Nil(t, it.wheelList)
+	assert.Nil(t, it.wheelElem)
+}
+
+// advance fires an item once real time reaches its expiry, not before.
+func TestTimingWheel_AdvanceFiresOnlyWhenDue(t *testing.T) {
+	now := time.Now()
+	w := newTimingWheel[string, string]()
+
+	it := newItem("a", "1", time.Millisecond*3, 0)
+	it.expireAt = now.Add(3 * time.Millisecond)
+	w.schedule(it, now)
+
+	var fired []string
+	fire := func(it *item[string, string]) { fired = append(fired, it.key) }
+
+	w.advance(now.Add(1*time.Millisecond), fire)
+	w.advance(now.Add(2*time.Millisecond), fire)
+	assert.Empty(t, fired)
+
+	w.advance(now.Add(3*time.Millisecond), fire)
+	assert.Equal(t, []string{"a"}, fired)
+}
@@ -0,0 +1,174 @@
+package ttlcache
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+)
+
+const cmSketchDepth = 4
+
+// countMinSketch is a small frequency estimator: depth independent hash
+// functions each vote into a row of width uint8 counters, and the estimate
+// for a key is the minimum of its votes (to cancel out collisions).
+type countMinSketch struct {
+	width int
+	rows  [cmSketchDepth][]uint8
+}
+
+func newCountMinSketch(width int) *countMinSketch {
+	if width <= 0 {
+		width = 1024
+	}
+	s := &countMinSketch{width: width}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+func (s *countMinSketch) add(key string) {
+	for row, col := range s.indices(key) {
+		if s.rows[row][col] < math.MaxUint8 {
+			s.rows[row][col]++
+		}
+	}
+}
+
+func (s *countMinSketch) estimate(key string) uint8 {
+	min := uint8(math.MaxUint8)
+	for row, col := range s.indices(key) {
+		if s.rows[row][col] < min {
+			min = s.rows[row][col]
+		}
+	}
+	return min
+}
+
+func (s *countMinSketch) indices(key string) [cmSketchDepth]int {
+	h1, h2 := hashPair(key)
+	var idx [cmSketchDepth]int
+	for i := range idx {
+		idx[i] = int((h1 + uint64(i)*h2) % uint64(s.width))
+	}
+	return idx
+}
+
+// doorkeeper is a simple Bloom filter used to recognize a key's *second*
+// sighting. A key's first sighting only sets its doorkeeper bits; only once
+// it reappears does it get counted in the count-min sketch, which keeps
+// one-hit-wonders from polluting the frequency estimate.
+type doorkeeper struct {
+	bits []uint64
+	size uint64
+}
+
+func newDoorkeeper(width int) *doorkeeper {
+	size := uint64(width) * 8
+	if size == 0 {
+		size = 8192
+	}
+	return &doorkeeper{bits: make([]uint64, (size+63)/64), size: size}
+}
+
+// seenBefore reports whether key was already present in the filter, and
+// marks it as present for next time.
+func (d *doorkeeper) seenBefore(key string) bool {
+	h1, h2 := hashPair(key)
+	seen := true
+	for i := 0; i < 3; i++ {
+		pos := (h1 + uint64(i)*h2) % d.size
+		word, bit := pos/64, uint(pos%64)
+		if d.bits[word]&(1<<bit) == 0 {
+			seen = false
+			d.bits[word] |= 1 << bit
+		}
+	}
+	return seen
+}
+
+func hashPair(key string) (uint64, uint64) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	h1 := h.Sum64()
+	h.Reset()
+	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte{0xff})
+	h2 := h.Sum64()
+	return h1, h2
+}
+
+// keyString renders a comparable key to a string for hashing purposes. It
+// doesn't need to be unique beyond what fmt already guarantees for the
+// types cache keys are realistically made of (strings, integers, small
+// structs), only cheap and stable for the lifetime of the sketch.
+func keyString[K comparable](key K) string {
+	if s, ok := any(key).(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", key)
+}
+
+// wTinyLFUPolicy is a W-TinyLFU admission policy: candidates for eviction
+// come from an LRU window, but a newly admitted key is only kept over that
+// window's least-recently-used victim if the count-min sketch estimates it
+// is accessed more often than the victim. Otherwise the new key itself is
+// evicted instead, protecting the cache from being flooded by a burst of
+// keys that are each only used once.
+type wTinyLFUPolicy[K comparable] struct {
+	window       *lruPolicy[K]
+	sketch       *countMinSketch
+	doorkeeper   *doorkeeper
+	lastAdded    K
+	hasLastAdded bool
+}
+
+// NewWTinyLFUPolicy returns a W-TinyLFU admission Policy. sketchWidth sizes
+// the internal count-min sketch and doorkeeper filter; a few times the
+// expected cache size limit is a reasonable choice.
+func NewWTinyLFUPolicy[K comparable](sketchWidth int) Policy[K] {
+	return &wTinyLFUPolicy[K]{
+		window:     NewLRUPolicy[K]().(*lruPolicy[K]),
+		sketch:     newCountMinSketch(sketchWidth),
+		doorkeeper: newDoorkeeper(sketchWidth),
+	}
+}
+
+func (p *wTinyLFUPolicy[K]) OnAdd(key K) {
+	p.window.OnAdd(key)
+	p.recordAccess(key)
+	p.lastAdded = key
+	p.hasLastAdded = true
+}
+
+func (p *wTinyLFUPolicy[K]) OnAccess(key K) {
+	p.window.OnAccess(key)
+	p.recordAccess(key)
+}
+
+func (p *wTinyLFUPolicy[K]) recordAccess(key K) {
+	k := keyString(key)
+	if p.doorkeeper.seenBefore(k) {
+		p.sketch.add(k)
+	}
+}
+
+func (p *wTinyLFUPolicy[K]) OnRemove(key K) {
+	p.window.OnRemove(key)
+	if p.hasLastAdded && p.lastAdded == key {
+		p.hasLastAdded = false
+	}
+}
+
+func (p *wTinyLFUPolicy[K]) Victim() (K, bool) {
+	victim, ok := p.window.Victim()
+	if !ok {
+		var zero K
+		return zero, false
+	}
+	if p.hasLastAdded && p.lastAdded != victim &&
+		p.sketch.estimate(keyString(p.lastAdded)) <= p.sketch.estimate(keyString(victim)) {
+		return p.lastAdded, true
+	}
+	return victim, true
+}
@@ -0,0 +1,33 @@
+package ttlcache
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SetTracer makes the cache emit spans for Get, loader invocations and
+// evictions through provider, each tagged with the key (and, for
+// evictions, the EvictionReason) as attributes. Pass nil to stop tracing.
+func (c *Cache[K, V]) SetTracer(provider trace.TracerProvider) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if provider == nil {
+		c.tracer = nil
+		return
+	}
+	c.tracer = provider.Tracer("github.com/witchu/ttlcache/v2")
+}
+
+// startSpan starts a span named name for key if tracing is enabled,
+// returning a no-op end func otherwise. tracer is the Cache's tracer field,
+// read by the caller under c.mutex.
+func startSpan[K comparable](tracer trace.Tracer, name string, key K, attrs ...attribute.KeyValue) func() {
+	if tracer == nil {
+		return func() {}
+	}
+	attrs = append([]attribute.KeyValue{attribute.String("key", keyString(key))}, attrs...)
+	_, span := tracer.Start(context.Background(), name, trace.WithAttributes(attrs...))
+	return func() { span.End() }
+}
@@ -0,0 +1,35 @@
+package ttlcache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/witchu/ttlcache/v2"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// SetTracer makes Get and evictions emit spans tagged with the key.
+func TestCache_SetTracer(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	cache := NewCache()
+	defer cache.Close()
+	cache.SetTracer(provider)
+
+	cache.SetTTL(time.Hour)
+	cache.Set("a", "1")
+	cache.Get("a")
+	assert.Equal(t, nil, cache.Remove("a"))
+
+	var names []string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+	}
+	assert.Contains(t, names, "Cache.Get")
+	assert.Contains(t, names, "Cache.Evict")
+}